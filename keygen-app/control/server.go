@@ -0,0 +1,68 @@
+// Package control runs the generator's HTTP control/metrics server:
+// Prometheus metrics, liveness/readiness probes, and a hot-reload endpoint
+// for the vanity suffix list.
+package control
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/sink"
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/vanity"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type suffixesRequest struct {
+	Suffixes []string `json:"suffixes"`
+}
+
+// NewServer builds the control/metrics HTTP server. It does not start
+// listening; call ListenAndServe (or Shutdown for graceful shutdown) on the
+// returned *http.Server.
+func NewServer(addr string, matcher *vanity.Matcher, sinks *sink.MultiSink) *http.Server {
+	mux := http.NewServeMux()
+
+	mux.Handle("/metrics", promhttp.Handler())
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !sinks.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("not ready"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ready"))
+	})
+
+	mux.HandleFunc("/suffixes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req suffixesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := matcher.ReloadSuffixes(req.Suffixes); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(suffixesRequest{Suffixes: matcher.Suffixes()})
+	})
+
+	return &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+}
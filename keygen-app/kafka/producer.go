@@ -1,13 +1,24 @@
 package kafka
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log"
+	"math/rand"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Shopify/sarama"
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/metrics"
 )
 
 const (
@@ -20,8 +31,207 @@ type KeyMessage struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+// TopicSpec picks the destination topic for a match. Select receives the
+// matched suffix and falls back to Default when it returns "".
+type TopicSpec struct {
+	Default string
+	Select  func(suffix string) string
+}
+
+func (t TopicSpec) topicFor(suffix string) string {
+	if t.Select != nil {
+		if topic := t.Select(suffix); topic != "" {
+			return topic
+		}
+	}
+	return t.Default
+}
+
+// DefaultTopicSpec routes case-variant "love" suffixes to dedicated topics so
+// a single dominant suffix doesn't flood ContractAddressesTopic on its own.
+func DefaultTopicSpec() TopicSpec {
+	return TopicSpec{
+		Default: ContractAddressesTopic,
+		Select: func(suffix string) string {
+			switch suffix {
+			case "LoVE", "LovE", "loVE", "LOVE":
+				return "love-caps"
+			case "love", "lovE":
+				return "love-lower"
+			default:
+				return ""
+			}
+		},
+	}
+}
+
+// KeyStrategy controls how the Kafka message key is derived for a match,
+// which in turn controls partitioning.
+type KeyStrategy string
+
+const (
+	KeyStrategyPublicKey KeyStrategy = "publickey"
+	KeyStrategySuffix    KeyStrategy = "suffix"
+	KeyStrategyHash      KeyStrategy = "hash"
+	KeyStrategyRandom    KeyStrategy = "random"
+)
+
+// KeySpec picks the Kafka message key for a match.
+type KeySpec struct {
+	Strategy KeyStrategy
+}
+
+func (k KeySpec) keyFor(pubKey, suffix string) string {
+	switch k.Strategy {
+	case KeyStrategySuffix:
+		return suffix
+	case KeyStrategyHash:
+		sum := sha256.Sum256([]byte(pubKey))
+		return hex.EncodeToString(sum[:])
+	case KeyStrategyRandom:
+		return strconv.FormatUint(rand.Uint64(), 10)
+	case KeyStrategyPublicKey, "":
+		return pubKey
+	default:
+		return pubKey
+	}
+}
+
+// unhealthyThreshold is how many consecutive publish errors mark the
+// producer unhealthy for Healthy()/readyz purposes.
+const unhealthyThreshold = 5
+
 type Producer struct {
-	producer sarama.SyncProducer
+	producer sarama.AsyncProducer
+	topics   TopicSpec
+	keys     KeySpec
+
+	pending           int64
+	consecutiveErrors int64
+	wg                sync.WaitGroup
+	publishTimeout    time.Duration
+}
+
+func buildConfig() (*sarama.Config, error) {
+	config := sarama.NewConfig()
+
+	config.Producer.RequiredAcks = requiredAcksFromEnv("KAFKA_REQUIRED_ACKS", sarama.WaitForAll)
+	config.Producer.Retry.Max = intFromEnv("KAFKA_RETRY_MAX", 5)
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+
+	config.Producer.Flush.Frequency = durationFromEnv("KAFKA_FLUSH_FREQUENCY_MS", 500*time.Millisecond)
+	config.Producer.Flush.Messages = intFromEnv("KAFKA_BATCH_SIZE", 100)
+
+	config.Producer.Compression = compressionFromEnv("KAFKA_COMPRESSION")
+
+	tlsConfig, err := tlsConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = tlsConfig
+	}
+
+	return config, nil
+}
+
+func intFromEnv(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return time.Duration(n) * time.Millisecond
+		}
+	}
+	return def
+}
+
+func requiredAcksFromEnv(key string, def sarama.RequiredAcks) sarama.RequiredAcks {
+	switch strings.ToLower(os.Getenv(key)) {
+	case "none":
+		return sarama.NoResponse
+	case "local", "one":
+		return sarama.WaitForLocal
+	case "all":
+		return sarama.WaitForAll
+	default:
+		return def
+	}
+}
+
+func compressionFromEnv(key string) sarama.CompressionCodec {
+	switch strings.ToLower(os.Getenv(key)) {
+	case "gzip":
+		return sarama.CompressionGZIP
+	case "lz4":
+		return sarama.CompressionLZ4
+	case "zstd":
+		return sarama.CompressionZSTD
+	case "none":
+		return sarama.CompressionNone
+	case "snappy", "":
+		return sarama.CompressionSnappy
+	default:
+		return sarama.CompressionSnappy
+	}
+}
+
+func keyStrategyFromEnv(key string) KeyStrategy {
+	switch strings.ToLower(os.Getenv(key)) {
+	case "suffix":
+		return KeyStrategySuffix
+	case "hash":
+		return KeyStrategyHash
+	case "random":
+		return KeyStrategyRandom
+	case "publickey", "":
+		return KeyStrategyPublicKey
+	default:
+		return KeyStrategyPublicKey
+	}
+}
+
+func tlsConfigFromEnv() (*tls.Config, error) {
+	certFile := os.Getenv("KAFKA_TLS_CERT_FILE")
+	keyFile := os.Getenv("KAFKA_TLS_KEY_FILE")
+	caFile := os.Getenv("KAFKA_TLS_CA_FILE")
+
+	if certFile == "" && keyFile == "" && caFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS cert/key: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS CA file: %v", err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse TLS CA file %s", caFile)
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	return tlsConfig, nil
 }
 
 func NewProducer() (*Producer, error) {
@@ -30,20 +240,76 @@ func NewProducer() (*Producer, error) {
 		return nil, fmt.Errorf("no Kafka brokers configured")
 	}
 
-	config := sarama.NewConfig()
-	config.Producer.RequiredAcks = sarama.WaitForAll
-	config.Producer.Retry.Max = 5
-	config.Producer.Return.Successes = true
+	config, err := buildConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	producer, err := sarama.NewSyncProducer(brokers, config)
+	asyncProducer, err := sarama.NewAsyncProducer(brokers, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create producer: %v", err)
 	}
 
-	return &Producer{producer: producer}, nil
+	p := &Producer{
+		producer:       asyncProducer,
+		topics:         DefaultTopicSpec(),
+		keys:           KeySpec{Strategy: keyStrategyFromEnv("KAFKA_KEY_STRATEGY")},
+		publishTimeout: durationFromEnv("KAFKA_PUBLISH_TIMEOUT_MS", 30*time.Second),
+	}
+
+	p.wg.Add(2)
+	go p.drainSuccesses()
+	go p.drainErrors()
+
+	return p, nil
+}
+
+// drainSuccesses and drainErrors correlate each produced message back to the
+// result channel PublishKey stashed in ProducerMessage.Metadata, so a
+// caller waiting on PublishKey only sees nil once the broker actually
+// acknowledged the message (or a concrete error otherwise).
+func (p *Producer) drainSuccesses() {
+	defer p.wg.Done()
+	for msg := range p.producer.Successes() {
+		atomic.AddInt64(&p.pending, -1)
+		atomic.StoreInt64(&p.consecutiveErrors, 0)
+		if result, ok := msg.Metadata.(chan error); ok {
+			result <- nil
+		}
+	}
+}
+
+func (p *Producer) drainErrors() {
+	defer p.wg.Done()
+	for prodErr := range p.producer.Errors() {
+		atomic.AddInt64(&p.pending, -1)
+		atomic.AddInt64(&p.consecutiveErrors, 1)
+		metrics.KafkaPublishErrors.Inc()
+		log.Printf("Error publishing key to Kafka: %v", prodErr.Err)
+		if result, ok := prodErr.Msg.Metadata.(chan error); ok {
+			result <- fmt.Errorf("failed to publish message: %v", prodErr.Err)
+		}
+	}
 }
 
-func (p *Producer) PublishKey(publicKey, privateKey string) error {
+// Healthy reports false once too many consecutive publishes have failed,
+// for use by the control server's /readyz check.
+func (p *Producer) Healthy() bool {
+	return atomic.LoadInt64(&p.consecutiveErrors) < unhealthyThreshold
+}
+
+// PublishKey enqueues a match onto the async producer and blocks until the
+// broker has actually acknowledged (or rejected) it, so a nil return is a
+// real delivery guarantee rather than just "accepted the write". Callers
+// that need a hard deadline should pass a context with a deadline; absent
+// one, KAFKA_PUBLISH_TIMEOUT_MS (default 30s) bounds the wait.
+func (p *Producer) PublishKey(ctx context.Context, publicKey, privateKey, suffix string) error {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.publishTimeout)
+		defer cancel()
+	}
+
 	msg := KeyMessage{
 		PublicKey:  publicKey,
 		PrivateKey: privateKey,
@@ -55,23 +321,53 @@ func (p *Producer) PublishKey(publicKey, privateKey string) error {
 		return fmt.Errorf("failed to marshal key message: %v", err)
 	}
 
+	result := make(chan error, 1)
 	message := &sarama.ProducerMessage{
-		Topic: ContractAddressesTopic,
-		Value: sarama.StringEncoder(jsonData),
-		Key:   sarama.StringEncoder(publicKey),
+		Topic:    p.topics.topicFor(suffix),
+		Value:    sarama.StringEncoder(jsonData),
+		Key:      sarama.StringEncoder(p.keys.keyFor(publicKey, suffix)),
+		Metadata: result,
 	}
 
-	_, _, err = p.producer.SendMessage(message)
-	if err != nil {
-		return fmt.Errorf("failed to send message: %v", err)
+	atomic.AddInt64(&p.pending, 1)
+
+	select {
+	case p.producer.Input() <- message:
+	case <-ctx.Done():
+		atomic.AddInt64(&p.pending, -1)
+		return fmt.Errorf("failed to enqueue message: %v", ctx.Err())
 	}
 
-	return nil
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for broker ack: %v", ctx.Err())
+	}
+}
+
+// Flush blocks until all messages handed to PublishKey have been acknowledged
+// (successfully or not) by the drain goroutines, or ctx is done.
+func (p *Producer) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if atomic.LoadInt64(&p.pending) <= 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("flush timed out with %d messages still in flight: %v", atomic.LoadInt64(&p.pending), ctx.Err())
+		}
+	}
 }
 
 func (p *Producer) Close() error {
 	if err := p.producer.Close(); err != nil {
 		return fmt.Errorf("failed to close producer: %v", err)
 	}
+	p.wg.Wait()
 	return nil
-} 
\ No newline at end of file
+}
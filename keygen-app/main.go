@@ -1,146 +1,158 @@
 package main
 
 import (
-	"bytes"
-	"crypto/rand"
+	"context"
 	"encoding/hex"
-	"encoding/json"
-	"fmt"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"runtime"
-	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
-	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/kafka"
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/control"
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/metrics"
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/sink"
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/vanity"
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/wal"
 	"github.com/mr-tron/base58"
-	ed25519 "golang.org/x/crypto/ed25519"
 )
 
+// batchSize controls how many keypairs vanity.BatchGenerate draws from the
+// CSPRNG at once per worker iteration.
+const batchSize = 64
+
+// counterFlushEvery bounds how often a worker's local generated-count is
+// folded into the shared atomic/Prometheus counters, so the hot path isn't
+// doing an atomic add (and a Prometheus counter add) per key.
+const counterFlushEvery = 1000
+
 var (
-	count          int
-	totalGenerated int
-	mutex          sync.Mutex
-	suffixes       []string
-	supabaseUrl    string
-	supabaseKey    string
-	kafkaProducer  *kafka.Producer
+	totalGenerated uint64
+	matchesTotal   uint64
+	matcher        *vanity.Matcher
+	matchSink      *sink.MultiSink
+	walStore       *wal.WAL
+	reconciler     *wal.Reconciler
 )
 
-type TokenContract struct {
-	PublicKey  string    `json:"public_key"`
-	PrivateKey string    `json:"private_key"`
-	CreatedAt  time.Time `json:"created_at"`
-}
-
 func init() {
-	// Load suffixes from env
-	suffixesEnv := os.Getenv("VANITY_SUFFIXES")
-	if suffixesEnv != "" {
-		suffixes = strings.Split(suffixesEnv, ",")
-	} else {
-		suffixes = []string{"LoVE", "LovE", "lovE", "love", "loVE"}
+	m, err := vanity.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure vanity matcher: %v", err)
 	}
+	matcher = m
 
-	// Initialize Supabase configuration
-	supabaseUrl = os.Getenv("SUPABASE_URL")
-	supabaseKey = os.Getenv("SUPABASE_KEY")
-	if supabaseUrl == "" || supabaseKey == "" {
-		log.Fatal("SUPABASE_URL and SUPABASE_KEY environment variables must be set")
+	// Build the configured sink fanout (Supabase, Kafka, file, S3, webhook, ...)
+	s, err := sink.BuildFromEnv(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to configure sinks: %v", err)
 	}
+	matchSink = s
 
-	// Initialize Kafka producer
-	producer, err := kafka.NewProducer()
+	walDir := os.Getenv("WAL_DIR")
+	if walDir == "" {
+		walDir = "./wal"
+	}
+	w, err := wal.New(walDir)
 	if err != nil {
-		log.Fatalf("Failed to create Kafka producer: %v", err)
+		log.Fatalf("Failed to open WAL: %v", err)
 	}
-	kafkaProducer = producer
+	walStore = w
+	reconciler = wal.NewReconciler(walStore, matchSink)
 }
 
-func storeInSupabase(contract TokenContract) error {
-	url := fmt.Sprintf("%s/rest/v1/token_contracts", supabaseUrl)
-	jsonData, err := json.Marshal(contract)
-	if err != nil {
-		return fmt.Errorf("failed to marshal contract: %v", err)
+// deliverMatch writes contract to the WAL before attempting delivery, and
+// only acks (deletes) the WAL record once every sink has accepted it. A
+// failed delivery is left for the reconciler to retry.
+func deliverMatch(ctx context.Context, contract sink.TokenContract) {
+	entry := wal.Entry{ID: contract.PublicKey, Contract: contract, CreatedAt: contract.CreatedAt}
+	if err := walStore.Write(entry); err != nil {
+		log.Printf("Error writing match to WAL: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sink.DefaultTimeout)
+		defer cancel()
 	}
 
-	req.Header.Set("apikey", supabaseKey)
-	req.Header.Set("Authorization", "Bearer "+supabaseKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Prefer", "return=minimal")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %v", err)
+	if err := matchSink.Write(ctx, contract); err != nil {
+		log.Printf("Error delivering match to sinks, left in WAL for retry: %v", err)
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("failed to store contract: status code %d", resp.StatusCode)
+	if err := walStore.Ack(contract.PublicKey); err != nil {
+		log.Printf("Error acking WAL entry: %v", err)
 	}
-
-	return nil
 }
 
-func generateKeyPairs(wg *sync.WaitGroup, jobs <-chan struct{}) {
+func generateKeyPairs(wg *sync.WaitGroup, stopChan <-chan struct{}) {
 	defer wg.Done()
-	for range jobs {
-		// Generate a new keypair
-		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	var localGenerated uint64
+
+	for {
+		select {
+		case <-stopChan:
+			atomic.AddUint64(&totalGenerated, localGenerated)
+			metrics.AddressesGenerated.Add(float64(localGenerated))
+			return
+		default:
+		}
+
+		publicKeys, privateKeys, err := vanity.BatchGenerate(batchSize)
 		if err != nil {
-			log.Printf("Error generating keypair: %v", err)
+			log.Printf("Error generating keypair batch: %v", err)
 			continue
 		}
 
-		// Convert public key to base58
-		pubKeyStr := base58.Encode(publicKey)
-
-		mutex.Lock()
-		totalGenerated++
-		// Check for any of the valid suffixes
-		for _, suffix := range suffixes {
-			if strings.HasSuffix(pubKeyStr, suffix) {
-				count++
-				privKeyHex := hex.EncodeToString(privateKey.Seed())
-				
-				// Store in Supabase
-				contract := TokenContract{
-					PublicKey:  pubKeyStr,
-					PrivateKey: privKeyHex,
-					CreatedAt:  time.Now(),
-				}
-				if err := storeInSupabase(contract); err != nil {
-					log.Printf("Error storing key in Supabase: %v", err)
-				}
-
-				// Publish to Kafka
-				if err := kafkaProducer.PublishKey(pubKeyStr, privKeyHex); err != nil {
-					log.Printf("Error publishing key to Kafka: %v", err)
-				}
-				break
+		for i, publicKey := range publicKeys {
+			pubKeyStr := base58.Encode(publicKey)
+
+			localGenerated++
+			if localGenerated >= counterFlushEvery {
+				atomic.AddUint64(&totalGenerated, localGenerated)
+				metrics.AddressesGenerated.Add(float64(localGenerated))
+				localGenerated = 0
+			}
+
+			suffix, ok := matcher.Match(pubKeyStr)
+			if !ok {
+				continue
 			}
+
+			atomic.AddUint64(&matchesTotal, 1)
+			metrics.Matches.WithLabelValues(suffix).Inc()
+			privKeyHex := hex.EncodeToString(privateKeys[i].Seed())
+
+			contract := sink.TokenContract{
+				PublicKey:  pubKeyStr,
+				PrivateKey: privKeyHex,
+				CreatedAt:  time.Now(),
+				Suffix:     suffix,
+			}
+			deliverMatch(context.Background(), contract)
 		}
-		mutex.Unlock()
 	}
 }
 
 func logProgress(stopChan <-chan struct{}) {
-	ticker := time.NewTicker(1 * time.Minute)
+	const interval = 1 * time.Minute
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	var lastGenerated uint64
 	for {
 		select {
 		case <-ticker.C:
-			mutex.Lock()
-			log.Printf("Generated %d total addresses, %d matching addresses with suffixes %v", totalGenerated, count, suffixes)
-			mutex.Unlock()
+			generated := atomic.LoadUint64(&totalGenerated)
+			metrics.KeysPerSecond.Set(float64(generated-lastGenerated) / interval.Seconds())
+			lastGenerated = generated
+			log.Printf("Generated %d total addresses, %d matching addresses", generated, atomic.LoadUint64(&matchesTotal))
 		case <-stopChan:
 			return
 		}
@@ -148,13 +160,37 @@ func logProgress(stopChan <-chan struct{}) {
 }
 
 func main() {
-	log.Println("Starting Solana vanity address generator...")
-	log.Printf("Looking for addresses with suffixes: %v", suffixes)
+	replay := flag.Bool("replay", false, "drain the WAL into the configured sinks and exit")
+	flag.Parse()
+
+	if *replay {
+		log.Println("Replaying WAL into sinks...")
+		if err := reconciler.Replay(context.Background()); err != nil {
+			log.Fatalf("Replay failed: %v", err)
+		}
+		log.Println("Replay complete.")
+		return
+	}
 
-	defer kafkaProducer.Close()
+	log.Println("Starting Solana vanity address generator...")
 
 	workers := runtime.NumCPU()
-	jobs := make(chan struct{}, workers*2)
+	metrics.Workers.Set(float64(workers))
+
+	httpAddr := os.Getenv("HTTP_ADDR")
+	if httpAddr == "" {
+		httpAddr = ":8080"
+	}
+	httpServer := control.NewServer(httpAddr, matcher, matchSink)
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Control server error: %v", err)
+		}
+	}()
+
+	reconcileCtx, stopReconciler := context.WithCancel(context.Background())
+	go reconciler.Run(reconcileCtx)
+
 	var wg sync.WaitGroup
 	stopChan := make(chan struct{})
 
@@ -164,16 +200,27 @@ func main() {
 	// Start workers
 	for i := 0; i < workers; i++ {
 		wg.Add(1)
-		go generateKeyPairs(&wg, jobs)
+		go generateKeyPairs(&wg, stopChan)
 	}
 
-	// Feed jobs efficiently
-	go func() {
-		for {
-			jobs <- struct{}{}
-		}
-	}()
+	// Shut down on SIGTERM/SIGINT: stop the workers, drain them, then close
+	// the control server and sinks.
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	<-sigChan
 
-	wg.Wait()
+	log.Println("Shutting down...")
 	close(stopChan)
-}
\ No newline at end of file
+	wg.Wait()
+	stopReconciler()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down control server: %v", err)
+	}
+
+	if err := matchSink.Close(); err != nil {
+		log.Printf("Error closing sinks: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+// Package metrics holds the Prometheus collectors shared across the
+// generator, sinks, and the control server.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	AddressesGenerated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "vanity_addresses_generated_total",
+		Help: "Total number of Solana addresses generated.",
+	})
+
+	Matches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vanity_matches_total",
+		Help: "Total number of addresses matching a configured vanity pattern, by suffix.",
+	}, []string{"suffix"})
+
+	KafkaPublishErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "kafka_publish_errors_total",
+		Help: "Total number of errors publishing a match to Kafka.",
+	})
+
+	SupabaseStoreErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "supabase_store_errors_total",
+		Help: "Total number of errors storing a match in Supabase.",
+	})
+
+	Workers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "vanity_workers",
+		Help: "Number of generator worker goroutines running.",
+	})
+
+	KeysPerSecond = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "vanity_keys_per_second",
+		Help: "Most recently observed keys/sec generation rate.",
+	})
+
+	WALDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "vanity_wal_depth",
+		Help: "Number of matches written to the WAL but not yet acknowledged by all sinks.",
+	})
+)
@@ -0,0 +1,66 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/kafka"
+)
+
+// BuildFromEnv builds the MultiSink described by the SINKS env var, a
+// comma-separated ordered list of sink names (default "supabase,kafka" to
+// match historical behavior). Each sink only reads the env vars it needs, so
+// operators can drop Supabase or Kafka entirely by leaving them out of SINKS.
+func BuildFromEnv(ctx context.Context) (*MultiSink, error) {
+	namesEnv := os.Getenv("SINKS")
+	if namesEnv == "" {
+		namesEnv = "supabase,kafka"
+	}
+
+	var sinks []Sink
+	for _, name := range strings.Split(namesEnv, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+
+		s, err := buildSink(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure sink %q: %v", name, err)
+		}
+		sinks = append(sinks, s)
+	}
+
+	return NewMultiSink(sinks...), nil
+}
+
+func buildSink(ctx context.Context, name string) (Sink, error) {
+	switch name {
+	case "supabase":
+		return NewSupabaseSink(os.Getenv("SUPABASE_URL"), os.Getenv("SUPABASE_KEY"))
+	case "kafka":
+		producer, err := kafka.NewProducer()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Kafka producer: %v", err)
+		}
+		return NewKafkaSink(producer), nil
+	case "file":
+		return NewFileSink(os.Getenv("FILE_SINK_PATH"))
+	case "s3":
+		return NewS3Sink(ctx, S3Config{
+			Endpoint:     os.Getenv("S3_ENDPOINT"),
+			Region:       os.Getenv("S3_REGION"),
+			Bucket:       os.Getenv("S3_BUCKET"),
+			Prefix:       os.Getenv("S3_PREFIX"),
+			AccessKey:    os.Getenv("S3_ACCESS_KEY"),
+			SecretKey:    os.Getenv("S3_SECRET_KEY"),
+			UsePathStyle: os.Getenv("S3_USE_PATH_STYLE") == "true",
+		})
+	case "webhook":
+		return NewWebhookSink(os.Getenv("WEBHOOK_URL"), os.Getenv("WEBHOOK_AUTH_HEADER"))
+	default:
+		return nil, fmt.Errorf("unknown sink %q", name)
+	}
+}
@@ -0,0 +1,52 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSink appends matches as newline-delimited JSON to a local file, useful
+// for running the generator without any network-backed sink configured.
+type FileSink struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file sink requires a path")
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file sink %s: %v", path, err)
+	}
+
+	return &FileSink{file: file}, nil
+}
+
+func (f *FileSink) Write(ctx context.Context, contract TokenContract) error {
+	jsonData, err := json.Marshal(contract)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contract: %v", err)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, err := f.file.Write(append(jsonData, '\n')); err != nil {
+		return fmt.Errorf("failed to append to file sink: %v", err)
+	}
+
+	return nil
+}
+
+func (f *FileSink) Close() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close file sink: %v", err)
+	}
+	return nil
+}
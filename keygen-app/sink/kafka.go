@@ -0,0 +1,36 @@
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/kafka"
+)
+
+// KafkaSink publishes matches onto the configured Kafka topic(s).
+type KafkaSink struct {
+	producer *kafka.Producer
+}
+
+func NewKafkaSink(producer *kafka.Producer) *KafkaSink {
+	return &KafkaSink{producer: producer}
+}
+
+func (k *KafkaSink) Write(ctx context.Context, contract TokenContract) error {
+	return k.producer.PublishKey(ctx, contract.PublicKey, contract.PrivateKey, contract.Suffix)
+}
+
+// Healthy reports whether the underlying Kafka producer is currently able to
+// publish, for MultiSink.Healthy()/the control server's /readyz check.
+func (k *KafkaSink) Healthy() bool {
+	return k.producer.Healthy()
+}
+
+func (k *KafkaSink) Close() error {
+	flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := k.producer.Flush(flushCtx); err != nil {
+		return err
+	}
+	return k.producer.Close()
+}
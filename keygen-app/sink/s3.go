@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Sink uploads each match as its own object, keyed by public key plus the
+// upload time in nanoseconds, to any S3-compatible object store (AWS S3,
+// MinIO, R2, Spaces, ...).
+type S3Sink struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+type S3Config struct {
+	Endpoint     string
+	Region       string
+	Bucket       string
+	Prefix       string
+	AccessKey    string
+	SecretKey    string
+	UsePathStyle bool
+}
+
+func NewS3Sink(ctx context.Context, cfg S3Config) (*S3Sink, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 sink requires a bucket")
+	}
+
+	loadOpts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRegion(cfg.Region),
+	}
+	if cfg.AccessKey != "" && cfg.SecretKey != "" {
+		loadOpts = append(loadOpts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKey, cfg.SecretKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load S3 config: %v", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+
+	return &S3Sink{client: client, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+func (s *S3Sink) Write(ctx context.Context, contract TokenContract) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
+
+	jsonData, err := json.Marshal(contract)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contract: %v", err)
+	}
+
+	key := fmt.Sprintf("%s%s-%d.json", s.prefix, contract.PublicKey, time.Now().UnixNano())
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(jsonData),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put S3 object: %v", err)
+	}
+
+	return nil
+}
+
+func (s *S3Sink) Close() error {
+	return nil
+}
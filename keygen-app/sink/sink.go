@@ -0,0 +1,105 @@
+// Package sink decouples match delivery from the generator so operators can
+// choose, combine, and reorder destinations (Supabase, Kafka, a local file,
+// S3, a webhook) via config instead of editing main.go.
+package sink
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds how long a single Write is allowed to take when the
+// caller hasn't already set a deadline on ctx. Sinks that make a blocking
+// network call (HTTP sinks' http.Client, S3's Write) use it so a hung
+// Supabase/webhook/S3 endpoint can't stall a generator worker forever.
+const DefaultTimeout = 30 * time.Second
+
+// TokenContract is the record written to every sink for a matched keypair.
+// Suffix is excluded from JSON output since not every sink's schema has a
+// column for it; sinks that route on it (e.g. Kafka) read the field directly.
+type TokenContract struct {
+	PublicKey  string    `json:"public_key"`
+	PrivateKey string    `json:"private_key"`
+	CreatedAt  time.Time `json:"created_at"`
+	Suffix     string    `json:"-"`
+}
+
+// Sink delivers a matched TokenContract to a single destination. Write must
+// block until the contract is durably accepted by the destination (e.g. a
+// Kafka produce must wait for the broker ack, not just enqueue) and only
+// return nil once that acknowledgement has happened — the WAL and its
+// reconciler (package wal) delete a match's WAL record the moment Write
+// returns nil, trusting it as proof of durable delivery.
+type Sink interface {
+	Write(ctx context.Context, contract TokenContract) error
+	Close() error
+}
+
+// HealthChecker is implemented by sinks that can report their own health,
+// e.g. KafkaSink tracking consecutive publish failures.
+type HealthChecker interface {
+	Healthy() bool
+}
+
+// MultiSink fans a single write out to every configured sink concurrently
+// and aggregates whichever ones fail.
+type MultiSink struct {
+	sinks []Sink
+}
+
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+func (m *MultiSink) Write(ctx context.Context, contract TokenContract) error {
+	errs := make([]error, len(m.sinks))
+
+	var wg sync.WaitGroup
+	for i, s := range m.sinks {
+		wg.Add(1)
+		go func(i int, s Sink) {
+			defer wg.Done()
+			if err := s.Write(ctx, contract); err != nil {
+				errs[i] = err
+			}
+		}(i, s)
+	}
+	wg.Wait()
+
+	return joinErrors(errs)
+}
+
+// Healthy reports false if any configured sink that implements
+// HealthChecker reports itself unhealthy. Sinks without a health check are
+// assumed healthy.
+func (m *MultiSink) Healthy() bool {
+	for _, s := range m.sinks {
+		if hc, ok := s.(HealthChecker); ok && !hc.Healthy() {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *MultiSink) Close() error {
+	errs := make([]error, len(m.sinks))
+	for i, s := range m.sinks {
+		errs[i] = s.Close()
+	}
+	return joinErrors(errs)
+}
+
+func joinErrors(errs []error) error {
+	var msgs []string
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d sink(s) failed: %v", len(msgs), msgs)
+}
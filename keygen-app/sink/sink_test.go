@@ -0,0 +1,62 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type fakeSink struct {
+	err error
+}
+
+func (f *fakeSink) Write(ctx context.Context, contract TokenContract) error {
+	return f.err
+}
+
+func (f *fakeSink) Close() error {
+	return nil
+}
+
+func TestMultiSinkWriteAggregatesPartialFailures(t *testing.T) {
+	ok1 := &fakeSink{}
+	failing := &fakeSink{err: fmt.Errorf("sink unavailable")}
+	ok2 := &fakeSink{}
+
+	m := NewMultiSink(ok1, failing, ok2)
+
+	err := m.Write(context.Background(), TokenContract{PublicKey: "pubkey1"})
+	if err == nil {
+		t.Fatal("expected an aggregated error when one sink fails")
+	}
+	if !strings.Contains(err.Error(), "1 sink(s) failed") {
+		t.Errorf("expected error to report exactly 1 failed sink, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "sink unavailable") {
+		t.Errorf("expected aggregated error to include the underlying sink error, got: %v", err)
+	}
+}
+
+func TestMultiSinkWriteAllSucceed(t *testing.T) {
+	m := NewMultiSink(&fakeSink{}, &fakeSink{}, &fakeSink{})
+
+	if err := m.Write(context.Background(), TokenContract{PublicKey: "pubkey1"}); err != nil {
+		t.Errorf("expected nil error when every sink succeeds, got %v", err)
+	}
+}
+
+func TestMultiSinkWriteAllFail(t *testing.T) {
+	m := NewMultiSink(
+		&fakeSink{err: fmt.Errorf("err1")},
+		&fakeSink{err: fmt.Errorf("err2")},
+	)
+
+	err := m.Write(context.Background(), TokenContract{PublicKey: "pubkey1"})
+	if err == nil {
+		t.Fatal("expected an aggregated error when every sink fails")
+	}
+	if !strings.Contains(err.Error(), "2 sink(s) failed") {
+		t.Errorf("expected error to report 2 failed sinks, got: %v", err)
+	}
+}
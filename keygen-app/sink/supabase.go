@@ -0,0 +1,65 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/metrics"
+)
+
+// SupabaseSink stores matches as rows in a Supabase (PostgREST) table.
+type SupabaseSink struct {
+	url    string
+	key    string
+	client *http.Client
+}
+
+func NewSupabaseSink(url, key string) (*SupabaseSink, error) {
+	if url == "" || key == "" {
+		return nil, fmt.Errorf("SUPABASE_URL and SUPABASE_KEY environment variables must be set")
+	}
+	return &SupabaseSink{url: url, key: key, client: &http.Client{Timeout: DefaultTimeout}}, nil
+}
+
+func (s *SupabaseSink) Write(ctx context.Context, contract TokenContract) (err error) {
+	defer func() {
+		if err != nil {
+			metrics.SupabaseStoreErrors.Inc()
+		}
+	}()
+
+	url := fmt.Sprintf("%s/rest/v1/token_contracts", s.url)
+	jsonData, err := json.Marshal(contract)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contract: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("apikey", s.key)
+	req.Header.Set("Authorization", "Bearer "+s.key)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "return=minimal")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("failed to store contract: status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *SupabaseSink) Close() error {
+	return nil
+}
@@ -0,0 +1,55 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookSink POSTs each match as JSON to an arbitrary HTTP endpoint.
+type WebhookSink struct {
+	url        string
+	authHeader string
+	client     *http.Client
+}
+
+func NewWebhookSink(url, authHeader string) (*WebhookSink, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook sink requires a URL")
+	}
+	return &WebhookSink{url: url, authHeader: authHeader, client: &http.Client{Timeout: DefaultTimeout}}, nil
+}
+
+func (w *WebhookSink) Write(ctx context.Context, contract TokenContract) error {
+	jsonData, err := json.Marshal(contract)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contract: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", w.url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.authHeader != "" {
+		req.Header.Set("Authorization", w.authHeader)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("webhook returned status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (w *WebhookSink) Close() error {
+	return nil
+}
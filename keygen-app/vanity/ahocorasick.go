@@ -0,0 +1,109 @@
+package vanity
+
+// ahoCorasick is a minimal Aho-Corasick automaton used to check an address
+// against every configured pattern in a single pass, instead of one
+// strings.HasSuffix/HasPrefix/Contains call per pattern.
+type ahoCorasick struct {
+	children []map[byte]int
+	fail     []int
+	output   [][]int // pattern indexes terminating at this node (including via fail links)
+	patterns []string
+}
+
+type occurrence struct {
+	patternIdx int
+	pattern    string
+	end        int // index one past the last matched byte in the scanned string
+}
+
+func newAhoCorasick(patterns []string) *ahoCorasick {
+	ac := &ahoCorasick{
+		children: []map[byte]int{make(map[byte]int)},
+		fail:     []int{0},
+		output:   [][]int{nil},
+		patterns: patterns,
+	}
+
+	for idx, pattern := range patterns {
+		node := 0
+		for i := 0; i < len(pattern); i++ {
+			c := pattern[i]
+			next, ok := ac.children[node][c]
+			if !ok {
+				ac.children = append(ac.children, make(map[byte]int))
+				ac.fail = append(ac.fail, 0)
+				ac.output = append(ac.output, nil)
+				next = len(ac.children) - 1
+				ac.children[node][c] = next
+			}
+			node = next
+		}
+		ac.output[node] = append(ac.output[node], idx)
+	}
+
+	ac.buildFailLinks()
+	return ac
+}
+
+func (ac *ahoCorasick) buildFailLinks() {
+	queue := make([]int, 0, len(ac.children))
+	for c, child := range ac.children[0] {
+		ac.fail[child] = 0
+		queue = append(queue, child)
+		_ = c
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for c, child := range ac.children[node] {
+			queue = append(queue, child)
+
+			fallback := ac.fail[node]
+			for {
+				if next, ok := ac.children[fallback][c]; ok && next != child {
+					ac.fail[child] = next
+					break
+				}
+				if fallback == 0 {
+					ac.fail[child] = 0
+					break
+				}
+				fallback = ac.fail[fallback]
+			}
+
+			ac.output[child] = append(ac.output[child], ac.output[ac.fail[child]]...)
+		}
+	}
+}
+
+// findAll returns every pattern occurrence in s, scanning s exactly once.
+func (ac *ahoCorasick) findAll(s string) []occurrence {
+	var occurrences []occurrence
+
+	node := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		for {
+			if next, ok := ac.children[node][c]; ok {
+				node = next
+				break
+			}
+			if node == 0 {
+				break
+			}
+			node = ac.fail[node]
+		}
+
+		for _, idx := range ac.output[node] {
+			occurrences = append(occurrences, occurrence{
+				patternIdx: idx,
+				pattern:    ac.patterns[idx],
+				end:        i + 1,
+			})
+		}
+	}
+
+	return occurrences
+}
@@ -0,0 +1,57 @@
+package vanity
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	ed25519 "golang.org/x/crypto/ed25519"
+)
+
+const seedSize = ed25519.SeedSize
+
+// BatchGenerate produces n ed25519 keypairs. It reads all seed material from
+// crypto/rand in a single draw instead of one rand.Read syscall per key, and
+// reuses a single SHA-512 hash.Hash (via Reset) across the whole batch
+// instead of the fresh digest context ed25519.NewKeyFromSeed allocates per
+// call, deriving the public key directly via edwards25519 scalar
+// arithmetic the way crypto/ed25519 does internally.
+func BatchGenerate(n int) ([]ed25519.PublicKey, []ed25519.PrivateKey, error) {
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("vanity: batch size must be positive, got %d", n)
+	}
+
+	seeds := make([]byte, n*seedSize)
+	if _, err := rand.Read(seeds); err != nil {
+		return nil, nil, fmt.Errorf("vanity: failed to read random seeds: %v", err)
+	}
+
+	h := sha512.New()
+	digest := make([]byte, 0, sha512.Size)
+
+	publicKeys := make([]ed25519.PublicKey, n)
+	privateKeys := make([]ed25519.PrivateKey, n)
+	for i := 0; i < n; i++ {
+		seed := seeds[i*seedSize : (i+1)*seedSize]
+
+		h.Reset()
+		h.Write(seed)
+		digest = h.Sum(digest[:0])
+
+		scalar, err := edwards25519.NewScalar().SetBytesWithClamping(digest[:32])
+		if err != nil {
+			return nil, nil, fmt.Errorf("vanity: failed to derive scalar from seed: %v", err)
+		}
+		pub := edwards25519.NewIdentityPoint().ScalarBaseMult(scalar).Bytes()
+
+		priv := make([]byte, ed25519.PrivateKeySize)
+		copy(priv[:seedSize], seed)
+		copy(priv[seedSize:], pub)
+
+		privateKeys[i] = ed25519.PrivateKey(priv)
+		publicKeys[i] = ed25519.PublicKey(pub)
+	}
+
+	return publicKeys, privateKeys, nil
+}
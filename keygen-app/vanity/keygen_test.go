@@ -0,0 +1,43 @@
+package vanity
+
+import (
+	"crypto/rand"
+	"testing"
+
+	ed25519 "golang.org/x/crypto/ed25519"
+)
+
+func BenchmarkGenerateKeySingle(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ed25519.GenerateKey(rand.Reader); err != nil {
+			b.Fatalf("failed to generate keypair: %v", err)
+		}
+	}
+}
+
+func BenchmarkBatchGenerate(b *testing.B) {
+	const batchSize = 64
+	b.ResetTimer()
+	for i := 0; i < b.N; i += batchSize {
+		if _, _, err := BatchGenerate(batchSize); err != nil {
+			b.Fatalf("failed to batch generate: %v", err)
+		}
+	}
+}
+
+func TestBatchGenerateProducesValidKeys(t *testing.T) {
+	publicKeys, privateKeys, err := BatchGenerate(8)
+	if err != nil {
+		t.Fatalf("failed to batch generate: %v", err)
+	}
+	if len(publicKeys) != 8 || len(privateKeys) != 8 {
+		t.Fatalf("expected 8 keypairs, got %d public, %d private", len(publicKeys), len(privateKeys))
+	}
+	for i := range publicKeys {
+		msg := []byte("vanity test message")
+		sig := ed25519.Sign(privateKeys[i], msg)
+		if !ed25519.Verify(publicKeys[i], msg, sig) {
+			t.Errorf("keypair %d failed sign/verify round trip", i)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package vanity matches generated Solana addresses against configured
+// vanity patterns and provides a batched keypair generator.
+package vanity
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Mode selects how patterns are matched against a generated address.
+type Mode string
+
+const (
+	ModeSuffix   Mode = "suffix"
+	ModePrefix   Mode = "prefix"
+	ModeContains Mode = "contains"
+	ModeRegex    Mode = "regex"
+)
+
+// matcherState is the immutable snapshot a Matcher points at. Reload swaps
+// in a new state rather than mutating one in place, so Match stays lock-free
+// for the worker pool even while a hot reload is in flight.
+type matcherState struct {
+	mode            Mode
+	caseInsensitive bool
+	patterns        []string     // original-case patterns, indexed like ac's pattern list
+	ac              *ahoCorasick // used for suffix/prefix/contains
+	re              *regexp.Regexp
+}
+
+// Matcher checks a base58-encoded address against a set of vanity patterns
+// in a single pass instead of one strings.HasSuffix call per pattern.
+type Matcher struct {
+	state atomic.Pointer[matcherState]
+}
+
+// NewFromEnv builds a Matcher from VANITY_SUFFIXES (default), VANITY_PREFIXES,
+// VANITY_CONTAINS or VANITY_REGEX, plus VANITY_CASE_INSENSITIVE. Exactly one
+// of the pattern env vars should be set; if more than one is present, the
+// first set of VANITY_REGEX, VANITY_PREFIXES, VANITY_CONTAINS is used, and
+// VANITY_SUFFIXES (or the built-in suffix list) only when none of them is.
+func NewFromEnv() (*Matcher, error) {
+	caseInsensitive := os.Getenv("VANITY_CASE_INSENSITIVE") == "true"
+
+	if regex := os.Getenv("VANITY_REGEX"); regex != "" {
+		return newRegexMatcher(regex, caseInsensitive)
+	}
+	if prefixes := os.Getenv("VANITY_PREFIXES"); prefixes != "" {
+		return newPatternMatcher(ModePrefix, splitPatterns(prefixes), caseInsensitive)
+	}
+	if contains := os.Getenv("VANITY_CONTAINS"); contains != "" {
+		return newPatternMatcher(ModeContains, splitPatterns(contains), caseInsensitive)
+	}
+
+	suffixesEnv := os.Getenv("VANITY_SUFFIXES")
+	var suffixes []string
+	if suffixesEnv != "" {
+		suffixes = splitPatterns(suffixesEnv)
+	} else {
+		suffixes = []string{"LoVE", "LovE", "lovE", "love", "loVE"}
+	}
+	return newPatternMatcher(ModeSuffix, suffixes, caseInsensitive)
+}
+
+func splitPatterns(env string) []string {
+	parts := strings.Split(env, ",")
+	patterns := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+func newMatcherState(mode Mode, patterns []string, caseInsensitive bool) (*matcherState, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("vanity: no patterns configured for mode %s", mode)
+	}
+
+	built := patterns
+	if caseInsensitive {
+		built = make([]string, len(patterns))
+		for i, p := range patterns {
+			built[i] = strings.ToLower(p)
+		}
+	}
+
+	return &matcherState{
+		mode:            mode,
+		caseInsensitive: caseInsensitive,
+		ac:              newAhoCorasick(built),
+		patterns:        patterns,
+	}, nil
+}
+
+func newPatternMatcher(mode Mode, patterns []string, caseInsensitive bool) (*Matcher, error) {
+	state, err := newMatcherState(mode, patterns, caseInsensitive)
+	if err != nil {
+		return nil, err
+	}
+	m := &Matcher{}
+	m.state.Store(state)
+	return m, nil
+}
+
+func newRegexMatcher(pattern string, caseInsensitive bool) (*Matcher, error) {
+	if caseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("vanity: invalid VANITY_REGEX: %v", err)
+	}
+	m := &Matcher{}
+	m.state.Store(&matcherState{mode: ModeRegex, caseInsensitive: caseInsensitive, re: re})
+	return m, nil
+}
+
+// Match reports whether addr satisfies the configured patterns in a single
+// pass, returning the specific pattern that matched (in regex mode, the
+// matched text of addr instead).
+func (m *Matcher) Match(addr string) (pattern string, ok bool) {
+	state := m.state.Load()
+
+	if state.mode == ModeRegex {
+		if loc := state.re.FindString(addr); loc != "" {
+			return loc, true
+		}
+		return "", false
+	}
+
+	scanAddr := addr
+	if state.caseInsensitive {
+		scanAddr = strings.ToLower(addr)
+	}
+
+	for _, occ := range state.ac.findAll(scanAddr) {
+		switch state.mode {
+		case ModeSuffix:
+			if occ.end == len(scanAddr) {
+				return state.patterns[occ.patternIdx], true
+			}
+		case ModePrefix:
+			if occ.end-len(occ.pattern) == 0 {
+				return state.patterns[occ.patternIdx], true
+			}
+		case ModeContains:
+			return state.patterns[occ.patternIdx], true
+		}
+	}
+
+	return "", false
+}
+
+// ReloadSuffixes hot-swaps the suffix list the Matcher checks against,
+// without restarting the generator. It only applies when the Matcher is
+// currently running in suffix mode; prefix/contains/regex matchers reject
+// reload since the control server's /suffixes endpoint is suffix-specific.
+func (m *Matcher) ReloadSuffixes(suffixes []string) error {
+	current := m.state.Load()
+	if current.mode != ModeSuffix {
+		return fmt.Errorf("vanity: cannot reload suffixes while running in %s mode", current.mode)
+	}
+
+	next, err := newMatcherState(ModeSuffix, suffixes, current.caseInsensitive)
+	if err != nil {
+		return err
+	}
+	m.state.Store(next)
+	return nil
+}
+
+// Suffixes returns the currently configured suffixes, or nil if the Matcher
+// isn't running in suffix mode.
+func (m *Matcher) Suffixes() []string {
+	state := m.state.Load()
+	if state.mode != ModeSuffix {
+		return nil
+	}
+	return append([]string(nil), state.patterns...)
+}
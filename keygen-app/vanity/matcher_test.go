@@ -0,0 +1,82 @@
+package vanity
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/mr-tron/base58"
+)
+
+func randomAddress(b *testing.B) string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		b.Fatalf("failed to read random bytes: %v", err)
+	}
+	return base58.Encode(buf)
+}
+
+// BenchmarkMatchSuffixNaive mirrors the original one-HasSuffix-per-pattern
+// loop, as a baseline for BenchmarkMatchSuffixAhoCorasick below.
+func BenchmarkMatchSuffixNaive(b *testing.B) {
+	suffixes := []string{"LoVE", "LovE", "lovE", "love", "loVE"}
+	addr := randomAddress(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, suffix := range suffixes {
+			if len(addr) >= len(suffix) && addr[len(addr)-len(suffix):] == suffix {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkMatchSuffixAhoCorasick(b *testing.B) {
+	m, err := newPatternMatcher(ModeSuffix, []string{"LoVE", "LovE", "lovE", "love", "loVE"}, false)
+	if err != nil {
+		b.Fatalf("failed to build matcher: %v", err)
+	}
+	addr := randomAddress(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Match(addr)
+	}
+}
+
+func TestMatcherModes(t *testing.T) {
+	suffixM, err := newPatternMatcher(ModeSuffix, []string{"love"}, false)
+	if err != nil {
+		t.Fatalf("failed to build suffix matcher: %v", err)
+	}
+	if _, ok := suffixM.Match("abclove"); !ok {
+		t.Error("expected suffix match on abclove")
+	}
+	if _, ok := suffixM.Match("loveabc"); ok {
+		t.Error("unexpected suffix match on loveabc")
+	}
+
+	prefixM, err := newPatternMatcher(ModePrefix, []string{"love"}, false)
+	if err != nil {
+		t.Fatalf("failed to build prefix matcher: %v", err)
+	}
+	if _, ok := prefixM.Match("loveabc"); !ok {
+		t.Error("expected prefix match on loveabc")
+	}
+
+	containsM, err := newPatternMatcher(ModeContains, []string{"love"}, false)
+	if err != nil {
+		t.Fatalf("failed to build contains matcher: %v", err)
+	}
+	if _, ok := containsM.Match("abcloveabc"); !ok {
+		t.Error("expected contains match on abcloveabc")
+	}
+
+	ciM, err := newPatternMatcher(ModeSuffix, []string{"love"}, true)
+	if err != nil {
+		t.Fatalf("failed to build case-insensitive matcher: %v", err)
+	}
+	if _, ok := ciM.Match("abcLOVE"); !ok {
+		t.Error("expected case-insensitive suffix match on abcLOVE")
+	}
+}
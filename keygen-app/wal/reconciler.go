@@ -0,0 +1,147 @@
+package wal
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/metrics"
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/sink"
+)
+
+const (
+	reconcileInterval = 5 * time.Second
+	initialBackoff    = 5 * time.Second
+	maxBackoff        = 5 * time.Minute
+)
+
+// Reconciler retries WAL entries that a sink failed to acknowledge, with
+// per-entry exponential backoff so a persistently-down sink doesn't turn
+// into a retry storm.
+type Reconciler struct {
+	wal   *WAL
+	sinks *sink.MultiSink
+
+	mu      sync.Mutex
+	backoff map[string]time.Duration
+	nextTry map[string]time.Time
+}
+
+func NewReconciler(w *WAL, sinks *sink.MultiSink) *Reconciler {
+	return &Reconciler{
+		wal:     w,
+		sinks:   sinks,
+		backoff: make(map[string]time.Duration),
+		nextTry: make(map[string]time.Time),
+	}
+}
+
+// Run re-reads unacknowledged entries on startup, then keeps retrying
+// whichever are due for a retry until ctx is cancelled.
+func (r *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	r.reconcileOnce(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			r.reconcileOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Reconciler) reconcileOnce(ctx context.Context) {
+	entries, err := r.wal.Pending()
+	if err != nil {
+		log.Printf("WAL reconciler: failed to list pending entries: %v", err)
+		return
+	}
+	metrics.WALDepth.Set(float64(len(entries)))
+
+	now := time.Now()
+	for _, entry := range entries {
+		r.mu.Lock()
+		due := r.nextTry[entry.ID]
+		r.mu.Unlock()
+		if !due.IsZero() && now.Before(due) {
+			continue
+		}
+
+		if err := r.writeOne(ctx, entry); err != nil {
+			log.Printf("WAL reconciler: delivery still failing for %s: %v", entry.ID, err)
+			r.bumpBackoff(entry.ID, now)
+			continue
+		}
+
+		if err := r.wal.Ack(entry.ID); err != nil {
+			log.Printf("WAL reconciler: failed to ack %s after successful delivery: %v", entry.ID, err)
+			continue
+		}
+		r.clearBackoff(entry.ID)
+	}
+}
+
+// writeOne bounds a single entry's delivery attempt to sink.DefaultTimeout so
+// one endpoint that's hanging (rather than cleanly failing) can't stall the
+// whole reconcile pass behind it.
+func (r *Reconciler) writeOne(ctx context.Context, entry Entry) error {
+	ctx, cancel := context.WithTimeout(ctx, sink.DefaultTimeout)
+	defer cancel()
+	return r.sinks.Write(ctx, entry.Contract)
+}
+
+func (r *Reconciler) bumpBackoff(id string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := r.backoff[id] * 2
+	if next < initialBackoff {
+		next = initialBackoff
+	}
+	if next > maxBackoff {
+		next = maxBackoff
+	}
+	r.backoff[id] = next
+	r.nextTry[id] = now.Add(next)
+}
+
+func (r *Reconciler) clearBackoff(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.backoff, id)
+	delete(r.nextTry, id)
+}
+
+// Replay drains every pending WAL entry into the sinks and returns once all
+// have been acknowledged (or ctx is cancelled), for the --replay CLI flag.
+func (r *Reconciler) Replay(ctx context.Context) error {
+	for {
+		entries, err := r.wal.Pending()
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		for _, entry := range entries {
+			if err := r.writeOne(ctx, entry); err != nil {
+				log.Printf("Replay: delivery failed for %s, will retry: %v", entry.ID, err)
+				select {
+				case <-time.After(initialBackoff):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				continue
+			}
+			if err := r.wal.Ack(entry.ID); err != nil {
+				return err
+			}
+			log.Printf("Replay: delivered and acked %s", entry.ID)
+		}
+	}
+}
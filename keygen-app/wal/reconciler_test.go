@@ -0,0 +1,53 @@
+package wal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconcilerBumpBackoffCapsAtMaxBackoff(t *testing.T) {
+	r := NewReconciler(nil, nil)
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		r.bumpBackoff("entry1", now)
+	}
+
+	r.mu.Lock()
+	got := r.backoff["entry1"]
+	r.mu.Unlock()
+
+	if got != maxBackoff {
+		t.Errorf("expected backoff to cap at %v after repeated bumps, got %v", maxBackoff, got)
+	}
+}
+
+func TestReconcilerBumpBackoffStartsAtInitialBackoff(t *testing.T) {
+	r := NewReconciler(nil, nil)
+
+	r.bumpBackoff("entry1", time.Now())
+
+	r.mu.Lock()
+	got := r.backoff["entry1"]
+	r.mu.Unlock()
+
+	if got != initialBackoff {
+		t.Errorf("expected first backoff to be %v, got %v", initialBackoff, got)
+	}
+}
+
+func TestReconcilerClearBackoff(t *testing.T) {
+	r := NewReconciler(nil, nil)
+	r.bumpBackoff("entry1", time.Now())
+
+	r.clearBackoff("entry1")
+
+	r.mu.Lock()
+	_, hasBackoff := r.backoff["entry1"]
+	_, hasNextTry := r.nextTry["entry1"]
+	r.mu.Unlock()
+
+	if hasBackoff || hasNextTry {
+		t.Error("expected clearBackoff to remove both backoff and nextTry entries")
+	}
+}
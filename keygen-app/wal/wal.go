@@ -0,0 +1,103 @@
+// Package wal is a write-ahead log for matched keypairs: a match is
+// recorded here before delivery is attempted to any sink, and only removed
+// once every sink has acknowledged it. This bounds the window in which an
+// expensive vanity hit can be lost to a Supabase/Kafka outage.
+package wal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/sink"
+)
+
+// Entry is one WAL record: a match awaiting acknowledgement from all sinks.
+type Entry struct {
+	ID        string             `json:"id"`
+	Contract  sink.TokenContract `json:"contract"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// WAL stores pending entries as one file per entry under dir, so
+// acknowledging an entry is a single os.Remove rather than a compaction
+// pass over an append-only log.
+type WAL struct {
+	dir string
+}
+
+func New(dir string) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("wal: failed to create WAL directory %s: %v", dir, err)
+	}
+	return &WAL{dir: dir}, nil
+}
+
+func (w *WAL) path(id string) string {
+	return filepath.Join(w.dir, id+".json")
+}
+
+// Write durably records entry before delivery to any sink is attempted.
+// It writes to a temp file and renames into place so a crash mid-write never
+// leaves a partial record behind.
+func (w *WAL) Write(entry Entry) error {
+	jsonData, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("wal: failed to marshal entry %s: %v", entry.ID, err)
+	}
+
+	finalPath := w.path(entry.ID)
+	tmpPath := finalPath + ".tmp"
+
+	if err := os.WriteFile(tmpPath, jsonData, 0644); err != nil {
+		return fmt.Errorf("wal: failed to write entry %s: %v", entry.ID, err)
+	}
+	if err := os.Rename(tmpPath, finalPath); err != nil {
+		return fmt.Errorf("wal: failed to commit entry %s: %v", entry.ID, err)
+	}
+
+	return nil
+}
+
+// Ack removes an entry once every configured sink has accepted it.
+func (w *WAL) Ack(id string) error {
+	if err := os.Remove(w.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("wal: failed to ack entry %s: %v", id, err)
+	}
+	return nil
+}
+
+// Pending returns every entry not yet acknowledged, for replay on startup or
+// after a transient sink failure.
+func (w *WAL) Pending() ([]Entry, error) {
+	matches, err := filepath.Glob(filepath.Join(w.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("wal: failed to list pending entries: %v", err)
+	}
+
+	entries := make([]Entry, 0, len(matches))
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Depth reports how many entries are currently unacknowledged.
+func (w *WAL) Depth() (int, error) {
+	matches, err := filepath.Glob(filepath.Join(w.dir, "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("wal: failed to count pending entries: %v", err)
+	}
+	return len(matches), nil
+}
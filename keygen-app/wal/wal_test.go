@@ -0,0 +1,79 @@
+package wal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mihirpenugonda/swifey-sentiment-agent/keygen-app/sink"
+)
+
+func TestWALWriteAckPendingDepth(t *testing.T) {
+	w, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create WAL: %v", err)
+	}
+
+	entry := Entry{
+		ID: "pubkey1",
+		Contract: sink.TokenContract{
+			PublicKey:  "pubkey1",
+			PrivateKey: "privkey1",
+			Suffix:     "love",
+		},
+		CreatedAt: time.Now(),
+	}
+	if err := w.Write(entry); err != nil {
+		t.Fatalf("failed to write entry: %v", err)
+	}
+
+	depth, err := w.Depth()
+	if err != nil {
+		t.Fatalf("failed to get depth: %v", err)
+	}
+	if depth != 1 {
+		t.Errorf("expected depth 1 after write, got %d", depth)
+	}
+
+	pending, err := w.Pending()
+	if err != nil {
+		t.Fatalf("failed to list pending entries: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending entry, got %d", len(pending))
+	}
+	if pending[0].ID != entry.ID || pending[0].Contract.PublicKey != entry.Contract.PublicKey {
+		t.Errorf("pending entry %+v did not round-trip entry %+v", pending[0], entry)
+	}
+
+	if err := w.Ack(entry.ID); err != nil {
+		t.Fatalf("failed to ack entry: %v", err)
+	}
+
+	depth, err = w.Depth()
+	if err != nil {
+		t.Fatalf("failed to get depth after ack: %v", err)
+	}
+	if depth != 0 {
+		t.Errorf("expected depth 0 after ack, got %d", depth)
+	}
+
+	// Acking an already-acked (or never-written) entry is not an error.
+	if err := w.Ack(entry.ID); err != nil {
+		t.Errorf("expected re-ack of missing entry to be a no-op, got %v", err)
+	}
+}
+
+func TestWALPendingEmpty(t *testing.T) {
+	w, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create WAL: %v", err)
+	}
+
+	pending, err := w.Pending()
+	if err != nil {
+		t.Fatalf("failed to list pending entries: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending entries for a fresh WAL, got %d", len(pending))
+	}
+}